@@ -0,0 +1,69 @@
+// Command goshs-genkey prints a single authfile-formatted credential
+// line, suitable for appending to the file passed to goshs's
+// --authfile or --bearer-file flags.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/patrickhener/goshs/internal/myauth"
+)
+
+func main() {
+	user := flag.String("user", "", "username (or label, for a bearer key) this credential is for")
+	password := flag.String("password", "", "password or bearer key to hash; if empty, read from stdin")
+	flag.Parse()
+
+	if *user == "" {
+		log.Fatalln("goshs-genkey: -user is required")
+	}
+
+	pw := *password
+	if pw == "" {
+		var err error
+		pw, err = readPassword()
+		if err != nil {
+			log.Fatalf("goshs-genkey: reading password: %+v\n", err)
+		}
+	}
+
+	line, err := myauth.HashCredential(*user, pw)
+	if err != nil {
+		log.Fatalf("goshs-genkey: %+v\n", err)
+	}
+
+	fmt.Println(line)
+}
+
+// readPassword prompts on stderr and reads a secret from stdin. On a
+// terminal it reads without echoing via term.ReadPassword; otherwise
+// (input piped from a file or another process) it falls back to
+// reading a full line, since term.ReadPassword requires a real tty.
+// Either way the whole line is kept, not just the text up to the first
+// space.
+func readPassword() (string, error) {
+	fmt.Fprint(os.Stderr, "Password: ")
+
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		b, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,87 @@
+// Command goshs serves a directory over HTTP, optionally behind TLS
+// and/or an authentication backend.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/patrickhener/goshs/internal/myhttp"
+)
+
+func main() {
+	port := flag.Int("port", 8000, "port to listen on")
+	webroot := flag.String("webroot", ".", "directory to serve")
+
+	ssl := flag.Bool("ssl", false, "serve over TLS")
+	selfSigned := flag.Bool("self-signed", false, "generate a self-signed certificate instead of using -key/-cert")
+	key := flag.String("key", "", "TLS private key (PEM), required with -ssl unless -self-signed")
+	cert := flag.String("cert", "", "TLS certificate (PEM), required with -ssl unless -self-signed")
+
+	basicAuth := flag.String("basic-auth", "", `password for the built-in "gopher" basic auth user`)
+	authFile := flag.String("authfile", "", `authfile of scrypt-hashed "user:hash" entries for basic auth, in place of -basic-auth`)
+	bearerFile := flag.String("bearer-file", "", "authfile of scrypt-hashed keys checked against the Authorization: Bearer header, in place of -basic-auth/-authfile")
+	unauthMethods := flag.String("unauth-methods", "", `comma-separated HTTP methods allowed through without authentication, e.g. "GET"`)
+
+	webdav := flag.Bool("webdav", false, "serve PUT/MKCOL/DELETE/MOVE/COPY via WebDAV alongside the HTML directory listing")
+
+	maxUploadSize := flag.Int64("max-upload-size", 0, "reject uploads larger than this many bytes (0 = unlimited)")
+
+	logJSON := flag.Bool("log-json", false, "emit the access log as single-line JSON instead of the human-readable format")
+	noLogs := flag.Bool("nologs", false, "suppress the access log entirely")
+	behindProxy := flag.Bool("behind-proxy", false, "log the client address from X-Forwarded-For/X-Real-IP instead of the socket's remote address")
+
+	hidden := flag.Bool("hidden", false, "allow serving and uploading dotfiles/dotdirs")
+	hideDotfiles := flag.Bool("hide-dotfiles", false, "report a hidden path as 404 instead of 401")
+
+	fastcgi := flag.Bool("fastcgi", false, "serve over FastCGI instead of plain HTTP")
+	socket := flag.String("socket", "", "listen on this Unix domain socket instead of a TCP port")
+	socketMode := flag.String("socket-mode", "0660", "file mode applied to -socket after it is created")
+
+	flag.Parse()
+
+	var methods []string
+	if *unauthMethods != "" {
+		methods = strings.Split(*unauthMethods, ",")
+	}
+
+	mode, err := strconv.ParseUint(*socketMode, 8, 32)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "goshs: invalid -socket-mode %q: %+v\n", *socketMode, err)
+		os.Exit(1)
+	}
+
+	fs := &myhttp.FileServer{
+		Port:       *port,
+		Webroot:    *webroot,
+		SSL:        *ssl,
+		SelfSigned: *selfSigned,
+		MyKey:      *key,
+		MyCert:     *cert,
+		BasicAuth:  *basicAuth,
+
+		AuthFile:      *authFile,
+		BearerFile:    *bearerFile,
+		UnauthMethods: methods,
+
+		WebDAV: *webdav,
+
+		MaxUploadSize: *maxUploadSize,
+
+		LogJSON:     *logJSON,
+		NoLogs:      *noLogs,
+		BehindProxy: *behindProxy,
+
+		Hidden:       *hidden,
+		HideDotfiles: *hideDotfiles,
+
+		FastCGI:    *fastcgi,
+		Socket:     *socket,
+		SocketMode: os.FileMode(mode),
+	}
+
+	fs.Start()
+}
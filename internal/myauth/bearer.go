@@ -0,0 +1,52 @@
+package myauth
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// BearerAuth checks an `Authorization: Bearer <key>` header against a
+// set of scrypt-hashed keys loaded from the same authfile format as
+// FileAuth, where the "username" column is a human-readable label for
+// the key rather than a login name.
+type BearerAuth struct {
+	keys map[string][]byte
+}
+
+// NewBearerAuth loads and parses path in the same format accepted by
+// NewFileAuth.
+func NewBearerAuth(path string) (*BearerAuth, error) {
+	entries, err := loadEntries(path)
+	if err != nil {
+		return nil, err
+	}
+	return &BearerAuth{keys: entries}, nil
+}
+
+// Authenticate implements Authenticator.
+func (a *BearerAuth) Authenticate(req *http.Request) (string, bool) {
+	header := req.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return "", false
+	}
+	key := strings.TrimPrefix(header, "Bearer ")
+
+	for label, hash := range a.keys {
+		if matches(key, hash) {
+			return label, true
+		}
+	}
+
+	return "", false
+}
+
+// Name implements Authenticator.
+func (a *BearerAuth) Name() string {
+	return fmt.Sprintf("bearer token (%d key(s))", len(a.keys))
+}
+
+// Challenge implements Authenticator.
+func (a *BearerAuth) Challenge() string {
+	return `Bearer realm="Restricted"`
+}
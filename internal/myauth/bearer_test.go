@@ -0,0 +1,42 @@
+package myauth
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestBearerAuthAuthenticate(t *testing.T) {
+	line, err := HashCredential("ci-key", "topsecretkey")
+	if err != nil {
+		t.Fatalf("HashCredential returned error: %+v", err)
+	}
+	path := writeAuthFile(t, line)
+
+	auth, err := NewBearerAuth(path)
+	if err != nil {
+		t.Fatalf("NewBearerAuth returned error: %+v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer topsecretkey")
+	if _, ok := auth.Authenticate(req); !ok {
+		t.Error("Authenticate(correct key) = false, want true")
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer wrongkey")
+	if _, ok := auth.Authenticate(req); ok {
+		t.Error("Authenticate(wrong key) = true, want false")
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+	if _, ok := auth.Authenticate(req); ok {
+		t.Error("Authenticate(non-bearer Authorization header) = true, want false")
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "/", nil)
+	if _, ok := auth.Authenticate(req); ok {
+		t.Error("Authenticate(no Authorization header) = true, want false")
+	}
+}
@@ -0,0 +1,80 @@
+package myauth
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// FileAuth checks HTTP basic auth credentials against a map of
+// username -> scrypt hash loaded once at startup from an authfile.
+type FileAuth struct {
+	entries map[string][]byte
+}
+
+// NewFileAuth loads and parses path, which must contain one
+// "user:scrypt(...)$hash" entry per line. Blank lines and lines
+// starting with "#" are ignored.
+func NewFileAuth(path string) (*FileAuth, error) {
+	entries, err := loadEntries(path)
+	if err != nil {
+		return nil, err
+	}
+	return &FileAuth{entries: entries}, nil
+}
+
+// Authenticate implements Authenticator.
+func (a *FileAuth) Authenticate(req *http.Request) (string, bool) {
+	username, password, ok := req.BasicAuth()
+	if !ok {
+		return "", false
+	}
+
+	hash, known := a.entries[username]
+	if !known || !matches(password, hash) {
+		return "", false
+	}
+
+	return username, true
+}
+
+// Name implements Authenticator.
+func (a *FileAuth) Name() string {
+	return fmt.Sprintf("authfile (%d credential(s))", len(a.entries))
+}
+
+// Challenge implements Authenticator.
+func (a *FileAuth) Challenge() string {
+	return `Basic realm="Restricted"`
+}
+
+func loadEntries(path string) (map[string][]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("myauth: opening authfile: %w", err)
+	}
+	defer f.Close()
+
+	entries := make(map[string][]byte)
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		identity, hash, err := parseEntry(line)
+		if err != nil {
+			return nil, fmt.Errorf("myauth: parsing authfile: %w", err)
+		}
+		entries[identity] = hash
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("myauth: reading authfile: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,69 @@
+package myauth
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeAuthFile(t *testing.T, entries ...string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "authfile")
+	content := ""
+	for _, e := range entries {
+		content += e + "\n"
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func basicAuthRequest(t *testing.T, username, password string) *http.Request {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.SetBasicAuth(username, password)
+	return req
+}
+
+func TestFileAuthAuthenticate(t *testing.T) {
+	line, err := HashCredential("gopher", "s3cret")
+	if err != nil {
+		t.Fatalf("HashCredential returned error: %+v", err)
+	}
+	path := writeAuthFile(t, "# a comment", "", line)
+
+	auth, err := NewFileAuth(path)
+	if err != nil {
+		t.Fatalf("NewFileAuth returned error: %+v", err)
+	}
+
+	if _, ok := auth.Authenticate(basicAuthRequest(t, "gopher", "s3cret")); !ok {
+		t.Error("Authenticate(correct credentials) = false, want true")
+	}
+	if _, ok := auth.Authenticate(basicAuthRequest(t, "gopher", "wrong")); ok {
+		t.Error("Authenticate(wrong password) = true, want false")
+	}
+	if _, ok := auth.Authenticate(basicAuthRequest(t, "nobody", "s3cret")); ok {
+		t.Error("Authenticate(unknown user) = true, want false")
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	if _, ok := auth.Authenticate(req); ok {
+		t.Error("Authenticate(no credentials) = true, want false")
+	}
+}
+
+func TestNewFileAuthRejectsMalformedFile(t *testing.T) {
+	path := writeAuthFile(t, "this-is-not-a-valid-entry")
+
+	if _, err := NewFileAuth(path); err == nil {
+		t.Fatal("NewFileAuth returned nil error for a malformed authfile")
+	}
+}
@@ -0,0 +1,40 @@
+package myauth
+
+import "net/http"
+
+// InlineAuth is the original goshs behavior: a single username/password
+// pair supplied on the command line, checked via HTTP basic auth.
+type InlineAuth struct {
+	Username string
+	Password string
+}
+
+// NewInlineAuth builds an InlineAuth from a plain-text username and
+// password pair.
+func NewInlineAuth(username, password string) *InlineAuth {
+	return &InlineAuth{Username: username, Password: password}
+}
+
+// Authenticate implements Authenticator.
+func (a *InlineAuth) Authenticate(req *http.Request) (string, bool) {
+	username, password, ok := req.BasicAuth()
+	if !ok {
+		return "", false
+	}
+
+	if username != a.Username || password != a.Password {
+		return "", false
+	}
+
+	return username, true
+}
+
+// Name implements Authenticator.
+func (a *InlineAuth) Name() string {
+	return "inline basic auth"
+}
+
+// Challenge implements Authenticator.
+func (a *InlineAuth) Challenge() string {
+	return `Basic realm="Restricted"`
+}
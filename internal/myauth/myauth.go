@@ -0,0 +1,61 @@
+// Package myauth provides pluggable authentication backends for the
+// goshs file server. An Authenticator decides, per request, whether
+// the caller supplied valid credentials.
+package myauth
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Authenticator is implemented by every supported auth backend.
+type Authenticator interface {
+	// Authenticate inspects the request and reports whether it carries
+	// valid credentials for this backend, along with the identity that
+	// was authenticated (used for logging only).
+	Authenticate(req *http.Request) (identity string, ok bool)
+
+	// Name identifies the backend for the startup log line.
+	Name() string
+
+	// Challenge returns the WWW-Authenticate header value a client
+	// should retry the request with, e.g. `Basic realm="Restricted"`.
+	Challenge() string
+}
+
+// Middleware wraps handler with auth, honoring the set of HTTP methods
+// that are allowed through without credentials. Methods in
+// unauthMethods are compared case-insensitively.
+func Middleware(auth Authenticator, unauthMethods []string, handler http.HandlerFunc) http.HandlerFunc {
+	skip := make(map[string]bool, len(unauthMethods))
+	for _, m := range unauthMethods {
+		skip[strings.ToUpper(m)] = true
+	}
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		if auth == nil || skip[strings.ToUpper(req.Method)] {
+			handler(w, req)
+			return
+		}
+
+		if _, ok := auth.Authenticate(req); !ok {
+			w.Header().Set("WWW-Authenticate", auth.Challenge())
+			http.Error(w, "Not authorized", http.StatusUnauthorized)
+			return
+		}
+
+		handler(w, req)
+	}
+}
+
+// constantTimeEquals compares two byte slices without leaking timing
+// information about where they first differ.
+func constantTimeEquals(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// errInvalidEntry is returned by parseEntry when a credential line does
+// not match the expected "user:spec$hash" format.
+var errInvalidEntry = fmt.Errorf("myauth: invalid credential entry")
@@ -0,0 +1,71 @@
+package myauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMiddlewareUnauthMethodsAreCaseInsensitive(t *testing.T) {
+	auth := NewInlineAuth("gopher", "s3cret")
+	handlerCalled := false
+	handler := func(w http.ResponseWriter, req *http.Request) { handlerCalled = true }
+
+	mw := Middleware(auth, []string{"get"}, handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	mw(w, req)
+
+	if !handlerCalled {
+		t.Fatal("GET was not let through by a lowercase-configured unauth method")
+	}
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestMiddlewareRequiresAuthForOtherMethods(t *testing.T) {
+	auth := NewInlineAuth("gopher", "s3cret")
+	handlerCalled := false
+	handler := func(w http.ResponseWriter, req *http.Request) { handlerCalled = true }
+
+	mw := Middleware(auth, []string{"GET"}, handler)
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	w := httptest.NewRecorder()
+	mw(w, req)
+
+	if handlerCalled {
+		t.Fatal("handler was called for an unauthenticated POST")
+	}
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestMiddlewareChallengesWithTheActiveBackendsScheme(t *testing.T) {
+	cases := []struct {
+		name string
+		auth Authenticator
+		want string
+	}{
+		{"inline", NewInlineAuth("gopher", "s3cret"), `Basic realm="Restricted"`},
+		{"bearer", &BearerAuth{}, `Bearer realm="Restricted"`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := func(w http.ResponseWriter, req *http.Request) {}
+			mw := Middleware(tc.auth, nil, handler)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			w := httptest.NewRecorder()
+			mw(w, req)
+
+			if got := w.Header().Get("WWW-Authenticate"); got != tc.want {
+				t.Fatalf("WWW-Authenticate = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
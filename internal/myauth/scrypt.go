@@ -0,0 +1,71 @@
+package myauth
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// scrypt parameters used for every hashed credential goshs writes or
+// reads. They are fixed (rather than encoded per-line) so entries stay
+// short and comparable; the spec string is still written in full for
+// readability and forward compatibility.
+const (
+	scryptN      = 16384
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+	scryptSalt   = "goshs"
+)
+
+// HashCredential returns a line in the format goshs expects in an
+// authfile: "user:scrypt(N=...,r=...,p=...,keyLen=...,salt="...")$hexhash".
+func HashCredential(user, password string) (string, error) {
+	sum, err := scryptSum(password)
+	if err != nil {
+		return "", fmt.Errorf("myauth: hashing credential: %w", err)
+	}
+	return fmt.Sprintf("%s:%s", user, encodeSpec(sum)), nil
+}
+
+func scryptSum(password string) ([]byte, error) {
+	return scrypt.Key([]byte(password), []byte(scryptSalt), scryptN, scryptR, scryptP, scryptKeyLen)
+}
+
+func encodeSpec(sum []byte) string {
+	return fmt.Sprintf("scrypt(N=%d,r=%d,p=%d,keyLen=%d,salt=%q)$%s", scryptN, scryptR, scryptP, scryptKeyLen, scryptSalt, hex.EncodeToString(sum))
+}
+
+// parseEntry splits a credential line into its identity and the raw
+// scrypt hash bytes it should match against.
+func parseEntry(line string) (identity string, hash []byte, err error) {
+	idx := strings.IndexByte(line, ':')
+	if idx < 0 {
+		return "", nil, errInvalidEntry
+	}
+	identity = line[:idx]
+
+	specIdx := strings.IndexByte(line[idx+1:], '$')
+	if specIdx < 0 {
+		return "", nil, errInvalidEntry
+	}
+	hexHash := line[idx+1+specIdx+1:]
+
+	hash, err = hex.DecodeString(strings.TrimSpace(hexHash))
+	if err != nil {
+		return "", nil, fmt.Errorf("myauth: decoding hash for %q: %w", identity, err)
+	}
+	return identity, hash, nil
+}
+
+// matches recomputes the scrypt hash for candidate and compares it
+// against the stored hash in constant time.
+func matches(candidate string, stored []byte) bool {
+	sum, err := scryptSum(candidate)
+	if err != nil {
+		return false
+	}
+	return constantTimeEquals(sum, stored)
+}
@@ -0,0 +1,39 @@
+package myauth
+
+import "testing"
+
+func TestHashCredentialParseEntryAndMatches(t *testing.T) {
+	line, err := HashCredential("gopher", "s3cret")
+	if err != nil {
+		t.Fatalf("HashCredential returned error: %+v", err)
+	}
+
+	identity, hash, err := parseEntry(line)
+	if err != nil {
+		t.Fatalf("parseEntry returned error: %+v", err)
+	}
+	if identity != "gopher" {
+		t.Fatalf("identity = %q, want %q", identity, "gopher")
+	}
+
+	if !matches("s3cret", hash) {
+		t.Fatal("matches(correct password) = false, want true")
+	}
+	if matches("wrong", hash) {
+		t.Fatal("matches(wrong password) = true, want false")
+	}
+}
+
+func TestParseEntryRejectsMalformedLines(t *testing.T) {
+	cases := []string{
+		"no-colon-here",
+		"gopher:no-dollar-sign",
+		"gopher:scrypt(...)$not-hex",
+	}
+
+	for _, line := range cases {
+		if _, _, err := parseEntry(line); err == nil {
+			t.Errorf("parseEntry(%q) returned nil error, want one", line)
+		}
+	}
+}
@@ -1,23 +1,50 @@
 package myhttp
 
 import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"io"
-	"io/ioutil"
 	"log"
+	"mime/multipart"
+	"net"
 	"net/http"
+	"net/http/fcgi"
 	"net/url"
 	"os"
 	"path"
+	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 
+	"golang.org/x/net/webdav"
+
+	"github.com/patrickhener/goshs/internal/myauth"
 	"github.com/patrickhener/goshs/internal/myca"
 	"github.com/patrickhener/goshs/internal/myhtml"
-	"github.com/patrickhener/goshs/internal/mylog"
 )
 
+// webdavMethods are dispatched to the WebDAV handler when --webdav is
+// enabled. GET is handled separately since it is shared with the HTML
+// directory listing.
+var webdavMethods = map[string]bool{
+	"PROPFIND":  true,
+	"PROPPATCH": true,
+	"MKCOL":     true,
+	"PUT":       true,
+	"DELETE":    true,
+	"MOVE":      true,
+	"COPY":      true,
+	"LOCK":      true,
+	"UNLOCK":    true,
+	"OPTIONS":   true,
+}
+
 type directory struct {
 	Path    string
 	Content []item
@@ -37,6 +64,58 @@ type FileServer struct {
 	MyKey      string
 	MyCert     string
 	BasicAuth  string
+
+	// AuthFile, when set, points to a newline-separated file of
+	// "user:scrypt(...)$hash" entries and switches auth from the
+	// single BasicAuth credential to the pluggable authfile backend.
+	AuthFile string
+	// BearerFile, when set, switches auth to bearer-token mode, reading
+	// `Authorization: Bearer <key>` and checking it against the
+	// scrypt-hashed keys in the file.
+	BearerFile string
+	// UnauthMethods lists HTTP methods that are let through without
+	// authentication, e.g. []string{"GET"} to allow anonymous browsing
+	// while still requiring auth for POST.
+	UnauthMethods []string
+
+	// WebDAV switches the server from the custom GET/POST handler to a
+	// full WebDAV handler rooted at Webroot, while still serving the
+	// HTML directory listing for browser GET requests.
+	WebDAV bool
+
+	// MaxUploadSize, when non-zero, caps how many bytes upload accepts
+	// for a single file; the partial temp file is removed if exceeded.
+	MaxUploadSize int64
+
+	// LogJSON emits each access log line as a single-line JSON record
+	// instead of the default human-readable line.
+	LogJSON bool
+	// NoLogs suppresses the access log entirely.
+	NoLogs bool
+	// BehindProxy makes the access log trust X-Forwarded-For/X-Real-IP
+	// for the client address instead of req.RemoteAddr.
+	BehindProxy bool
+
+	// Hidden allows serving and uploading dotfiles/dotdirs, which are
+	// rejected by default.
+	Hidden bool
+	// HideDotfiles makes the dotfile rejection look like a 404 instead
+	// of the default 401, so a share doesn't even reveal that hidden
+	// paths exist.
+	HideDotfiles bool
+
+	// FastCGI serves the listener via the FastCGI protocol (net/http/fcgi)
+	// instead of plain HTTP, for use behind nginx/Caddy.
+	FastCGI bool
+	// Socket, when set, makes Start listen on this Unix domain socket
+	// path instead of a TCP port.
+	Socket string
+	// SocketMode is the file mode applied to Socket after it is
+	// created. Defaults to 0660.
+	SocketMode os.FileMode
+
+	authenticator myauth.Authenticator
+	davHandler    *webdav.Handler
 }
 
 // router will hook up the webroot with our fileserver
@@ -44,101 +123,219 @@ func (fs *FileServer) router() {
 	http.Handle("/", fs)
 }
 
-// authRouter will hook up the webroot with the fileserver using basic auth
+// authRouter will hook up the webroot with the fileserver behind the
+// configured Authenticator
 func (fs *FileServer) authRouter() {
-	http.HandleFunc("/", fs.basicAuth(fs.ServeHTTP))
+	http.HandleFunc("/", myauth.Middleware(fs.authenticator, fs.UnauthMethods, fs.ServeHTTP))
 }
 
-// basicAuth is a wrapper to handle the basic auth
-func (fs *FileServer) basicAuth(handler http.HandlerFunc) func(w http.ResponseWriter, req *http.Request) {
-	return func(w http.ResponseWriter, req *http.Request) {
-		w.Header().Set("WWW-Authenticate", `Basic realm="Restricted"`)
-
-		username, password, authOK := req.BasicAuth()
-		if authOK == false {
-			http.Error(w, "Not authorized", http.StatusUnauthorized)
-			return
+// setupAuth picks an Authenticator based on the configured flags. It
+// returns nil if no auth is configured.
+func (fs *FileServer) setupAuth() error {
+	switch {
+	case fs.AuthFile != "":
+		auth, err := myauth.NewFileAuth(fs.AuthFile)
+		if err != nil {
+			return err
 		}
-
-		if username != "gopher" || password != fs.BasicAuth {
-			http.Error(w, "Not authorized", http.StatusUnauthorized)
-			return
+		fs.authenticator = auth
+	case fs.BearerFile != "":
+		auth, err := myauth.NewBearerAuth(fs.BearerFile)
+		if err != nil {
+			return err
 		}
-
-		fs.ServeHTTP(w, req)
+		fs.authenticator = auth
+	case fs.BasicAuth != "":
+		fs.authenticator = myauth.NewInlineAuth("gopher", fs.BasicAuth)
+	default:
+		fs.authenticator = nil
 	}
+
+	return nil
 }
 
 // Start will start the file server
 func (fs *FileServer) Start() {
 	// init router with or without auth
-	if fs.BasicAuth != "" {
+	if err := fs.setupAuth(); err != nil {
+		log.Fatalf("Unable to set up authentication: %+v\n", err)
+	}
+
+	if fs.authenticator != nil {
 		if !fs.SSL {
-			log.Printf("WARNING!: You are using basic auth without SSL. Your credentials will be transfered in cleartext. Consider using -s, too.\n")
+			log.Printf("WARNING!: You are using auth without SSL. Your credentials will be transfered in cleartext. Consider using -s, too.\n")
 		}
-		log.Printf("Using 'gopher:%+v' as basic auth\n", fs.BasicAuth)
+		log.Printf("Using %s as authentication backend\n", fs.authenticator.Name())
 		fs.authRouter()
 	} else {
 		fs.router()
 	}
 
-	// construct server
-	add := fmt.Sprintf(":%+v", fs.Port)
-	server := http.Server{Addr: add}
+	if fs.WebDAV {
+		fs.davHandler = &webdav.Handler{
+			FileSystem: newSafeWebdavFS(fs.Webroot, fs.Hidden),
+			LockSystem: webdav.NewMemLS(),
+		}
+		log.Println("WebDAV read/write mode enabled, PUT/MKCOL/DELETE/MOVE/COPY are available on this share")
+	}
+
+	l, err := fs.listen()
+	if err != nil {
+		log.Fatalf("Unable to start listener: %+v\n", err)
+	}
 
-	// Check if ssl
 	if fs.SSL {
-		// Check if selfsigned
-		if fs.SelfSigned {
-			serverTLSConf, fingerprint256, fingerprint1, err := myca.Setup()
-			if err != nil {
-				log.Fatalf("Unable to start SSL enabled server: %+v\n", err)
-			}
-			server.TLSConfig = serverTLSConf
-			log.Printf("Serving HTTP on 0.0.0.0 port %+v from %+v with ssl enabled and self-signed certificate\n", fs.Port, fs.Webroot)
-			log.Println("WARNING! Be sure to check the fingerprint of certificate")
-			log.Printf("SHA-256 Fingerprint: %+v\n", fingerprint256)
-			log.Printf("SHA-1   Fingerprint: %+v\n", fingerprint1)
-			log.Panic(server.ListenAndServeTLS("", ""))
-		} else {
-			if fs.MyCert == "" || fs.MyKey == "" {
-				log.Fatalln("You need to provide server.key and server.crt if -s and not -ss")
-			}
-
-			fingerprint256, fingerprint1, err := myca.ParseAndSum(fs.MyCert)
-			if err != nil {
-				log.Fatalf("Unable to start SSL enabled server: %+v\n", err)
-			}
-
-			log.Printf("Serving HTTP on 0.0.0.0 port %+v from %+v with ssl enabled server key: %+v, server cert: %+v\n", fs.Port, fs.Webroot, fs.MyKey, fs.MyCert)
-			log.Println("INFO! You provided a certificate and might want to check the fingerprint nonetheless")
-			log.Printf("SHA-256 Fingerprint: %+v\n", fingerprint256)
-			log.Printf("SHA-1   Fingerprint: %+v\n", fingerprint1)
-
-			log.Panic(server.ListenAndServeTLS(fs.MyCert, fs.MyKey))
+		tlsConf, err := fs.tlsConfig()
+		if err != nil {
+			log.Fatalf("Unable to start SSL enabled server: %+v\n", err)
 		}
-	} else {
-		log.Printf("Serving HTTP on 0.0.0.0 port %+v from %+v\n", fs.Port, fs.Webroot)
-		log.Panic(server.ListenAndServe())
+		l = tls.NewListener(l, tlsConf)
+	}
+
+	protocol := "HTTP"
+	if fs.FastCGI {
+		protocol = "FastCGI"
+	}
+	log.Printf("Serving %s on %+v from %+v (ssl: %+v)\n", protocol, l.Addr(), fs.Webroot, fs.SSL)
+
+	if fs.FastCGI {
+		log.Panic(fcgi.Serve(l, nil))
+		return
+	}
+
+	log.Panic(new(http.Server).Serve(l))
+}
+
+// listen picks a net.Listener for Start based on the --socket flag,
+// falling back to a plain TCP listener on fs.Port. TLS wrapping and
+// the FastCGI/HTTP protocol choice are layered on top by the caller,
+// so the result is usable identically across all listener types.
+func (fs *FileServer) listen() (net.Listener, error) {
+	if fs.Socket != "" {
+		if err := os.RemoveAll(fs.Socket); err != nil {
+			return nil, fmt.Errorf("removing stale socket: %w", err)
+		}
+
+		l, err := net.Listen("unix", fs.Socket)
+		if err != nil {
+			return nil, err
+		}
+
+		mode := fs.SocketMode
+		if mode == 0 {
+			mode = 0660
+		}
+		if err := os.Chmod(fs.Socket, mode); err != nil {
+			return nil, fmt.Errorf("setting socket mode: %w", err)
+		}
+
+		return l, nil
+	}
+
+	return net.Listen("tcp", fmt.Sprintf(":%d", fs.Port))
+}
+
+// tlsConfig builds the *tls.Config to wrap a listener in when SSL is
+// enabled, logging the certificate fingerprint exactly as before.
+func (fs *FileServer) tlsConfig() (*tls.Config, error) {
+	if fs.SelfSigned {
+		conf, fingerprint256, fingerprint1, err := myca.Setup()
+		if err != nil {
+			return nil, err
+		}
+
+		log.Println("WARNING! Be sure to check the fingerprint of certificate")
+		log.Printf("SHA-256 Fingerprint: %+v\n", fingerprint256)
+		log.Printf("SHA-1   Fingerprint: %+v\n", fingerprint1)
+
+		return conf, nil
 	}
+
+	if fs.MyCert == "" || fs.MyKey == "" {
+		return nil, fmt.Errorf("you need to provide server.key and server.crt if -s and not -ss")
+	}
+
+	fingerprint256, fingerprint1, err := myca.ParseAndSum(fs.MyCert)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Println("INFO! You provided a certificate and might want to check the fingerprint nonetheless")
+	log.Printf("SHA-256 Fingerprint: %+v\n", fingerprint256)
+	log.Printf("SHA-1   Fingerprint: %+v\n", fingerprint1)
+
+	cert, err := tls.LoadX509KeyPair(fs.MyCert, fs.MyKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{Certificates: []tls.Certificate{cert}}, nil
 }
 
 // ServeHTTP will serve the response by leveraging our handler
 func (fs *FileServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	fs.withLogging(fs.dispatch)(w, req)
+}
+
+// dispatch is the actual request router, wrapped by the access log
+// middleware in ServeHTTP.
+func (fs *FileServer) dispatch(w http.ResponseWriter, req *http.Request) {
 	defer func() {
 		if err := recover(); err != nil {
 			http.Error(w, fmt.Sprintf("%+v", err), http.StatusInternalServerError)
 		}
 	}()
 
-	switch req.Method {
-	case "GET":
+	switch {
+	case fs.WebDAV && webdavMethods[req.Method]:
+		fs.webdav().ServeHTTP(w, req)
+	case req.Method == "GET" && fs.WebDAV && wantsWebDAV(req):
+		fs.webdav().ServeHTTP(w, req)
+	case req.Method == "GET":
 		fs.handler(w, req)
-	case "POST":
+	case req.Method == "POST":
 		fs.upload(w, req)
 	}
 }
 
+// webdav returns the WebDAV handler built once in Start when --webdav
+// is set. Its requests are covered by the same access log middleware
+// as the rest of ServeHTTP, so no separate Logger is configured here.
+func (fs *FileServer) webdav() *webdav.Handler {
+	return fs.davHandler
+}
+
+// wantsWebDAV decides, for a GET request, whether the client is a
+// WebDAV client (davfs2, Windows Explorer, rclone, ...) expecting raw
+// file semantics rather than the HTML directory listing.
+func wantsWebDAV(req *http.Request) bool {
+	if strings.Contains(req.Header.Get("Accept"), "text/html") {
+		return false
+	}
+
+	ua := req.Header.Get("User-Agent")
+	for _, marker := range []string{"Microsoft-WebDAV-MiniRedir", "davfs2", "rclone", "WebDAVFS", "gvfs"} {
+		if strings.Contains(ua, marker) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// handleSafeJoinError writes the appropriate rejection response for an
+// error returned by safeJoin. A hidden-path rejection honors
+// fs.HideDotfiles (401 by default, 404 if set); anything else
+// (traversal, symlink escape, ...) is reported as a plain 404 so it
+// reveals nothing about the filesystem.
+func (fs *FileServer) handleSafeJoinError(w http.ResponseWriter, req *http.Request, err error) {
+	if errors.Is(err, errHiddenPath) && !fs.HideDotfiles {
+		http.Error(w, "Not authorized", http.StatusUnauthorized)
+		return
+	}
+	fs.handle404(w, req)
+}
+
 // handler is the function which actually handles dir or file retrieval
 func (fs *FileServer) handler(w http.ResponseWriter, req *http.Request) {
 	// Get url so you can extract Headline and title
@@ -149,8 +346,13 @@ func (fs *FileServer) handler(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	// Define absolute path
-	open := fs.Webroot + path.Clean(upath)
+	// Define absolute path, safe against traversal, dotfile exposure
+	// and symlink escape
+	open, err := safeJoin(fs.Webroot, upath, fs.Hidden)
+	if err != nil {
+		fs.handleSafeJoinError(w, req, err)
+		return
+	}
 
 	// Check if you are in a dir
 	file, err := os.Open(open)
@@ -171,9 +373,6 @@ func (fs *FileServer) handler(w http.ResponseWriter, req *http.Request) {
 	}
 	defer file.Close()
 
-	// Log request
-	mylog.LogRequest(req.RemoteAddr, req.Method, req.URL.Path, req.Proto, "200")
-
 	// Switch and check if dir
 	stat, _ := file.Stat()
 	if stat.IsDir() {
@@ -183,17 +382,89 @@ func (fs *FileServer) handler(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
-// upload handles the POST request to upload files
-func (fs *FileServer) upload(w http.ResponseWriter, req *http.Request) {
-	req.ParseMultipartForm(10 << 20)
+// uploadResult is the JSON body returned from upload when the client
+// sends "Accept: application/json".
+type uploadResult struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
 
-	file, handler, err := req.FormFile("file")
+// contentRange is a parsed "Content-Range: bytes X-Y/Z" header, used to
+// resume an interrupted upload.
+type contentRange struct {
+	start, end, total int64
+}
+
+// parseContentRange parses a Content-Range header. It returns nil, nil
+// if header is empty, meaning the upload is not chunked/resumed.
+func parseContentRange(header string) (*contentRange, error) {
+	if header == "" {
+		return nil, nil
+	}
+
+	header = strings.TrimPrefix(header, "bytes ")
+	spanAndTotal := strings.SplitN(header, "/", 2)
+	if len(spanAndTotal) != 2 {
+		return nil, fmt.Errorf("malformed Content-Range header")
+	}
+
+	total, err := strconv.ParseInt(spanAndTotal[1], 10, 64)
 	if err != nil {
-		log.Printf("Error retrieving the file: %+v\n", err)
+		return nil, fmt.Errorf("malformed Content-Range total")
 	}
-	defer file.Close()
 
-	// Get url so you can extract Headline and title
+	startAndEnd := strings.SplitN(spanAndTotal[0], "-", 2)
+	if len(startAndEnd) != 2 {
+		return nil, fmt.Errorf("malformed Content-Range span")
+	}
+
+	start, err := strconv.ParseInt(startAndEnd[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("malformed Content-Range start")
+	}
+	end, err := strconv.ParseInt(startAndEnd[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("malformed Content-Range end")
+	}
+
+	return &contentRange{start: start, end: end, total: total}, nil
+}
+
+// filePart returns the first multipart part named "file".
+func filePart(mr *multipart.Reader) (*multipart.Part, error) {
+	for {
+		part, err := mr.NextPart()
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == "file" {
+			return part, nil
+		}
+	}
+}
+
+// sha256File streams path and returns its hex-encoded SHA-256 sum.
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// upload handles the POST request to upload files. It streams the
+// request body straight to a temp file next to the final destination,
+// supports resuming an interrupted upload via Content-Range and
+// enforces --max-upload-size while the copy is in flight.
+func (fs *FileServer) upload(w http.ResponseWriter, req *http.Request) {
 	upath := req.URL.Path
 
 	// construct target path
@@ -201,30 +472,138 @@ func (fs *FileServer) upload(w http.ResponseWriter, req *http.Request) {
 	targetpath = targetpath[:len(targetpath)-1]
 	target := strings.Join(targetpath, "/")
 
-	// Construct absolute savepath
-	savepath := fmt.Sprintf("%s%s/%s", fs.Webroot, target, handler.Filename)
+	mr, err := req.MultipartReader()
+	if err != nil {
+		log.Printf("Error reading multipart upload: %+v\n", err)
+		fs.handle500(w, req)
+		return
+	}
+
+	part, err := filePart(mr)
+	if err != nil {
+		log.Printf("Error retrieving the file: %+v\n", err)
+		fs.handle500(w, req)
+		return
+	}
 
-	// Create file to write to
-	if _, err := os.Create(savepath); err != nil {
+	filename := filepath.Base(part.FileName())
+
+	// safeJoin checks the full destination path (target dir and
+	// filename), not just the leaf name, against traversal, symlink
+	// escape and dotfile exposure.
+	savepath, err := safeJoin(fs.Webroot, path.Join(target, filename), fs.Hidden)
+	if err != nil {
+		fs.handleSafeJoinError(w, req, err)
+		return
+	}
+	temppath := savepath + ".goshs-upload"
+
+	cr, err := parseContentRange(req.Header.Get("Content-Range"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestedRangeNotSatisfiable)
+		return
+	}
+
+	var offset int64
+	final := true
+	if cr != nil {
+		offset = cr.start
+		final = cr.end+1 >= cr.total
+	}
+
+	if ifNoneMatch := req.Header.Get("If-None-Match"); ifNoneMatch == "*" {
+		if _, err := os.Stat(savepath); err == nil {
+			http.Error(w, "File already exists", http.StatusPreconditionFailed)
+			return
+		}
+	}
+	if ifMatch := req.Header.Get("If-Match"); ifMatch != "" {
+		existing, err := sha256File(savepath)
+		if err != nil || existing != strings.Trim(ifMatch, `"`) {
+			http.Error(w, "ETag does not match existing file", http.StatusPreconditionFailed)
+			return
+		}
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE
+	if offset > 0 {
+		flags |= os.O_APPEND
+	} else {
+		flags |= os.O_TRUNC
+	}
+
+	out, err := os.OpenFile(temppath, flags, os.ModePerm)
+	if err != nil {
 		log.Println("ERROR:   Not able to create file on disk")
 		fs.handle500(w, req)
+		return
+	}
+	defer out.Close()
+
+	if offset > 0 {
+		stat, err := out.Stat()
+		if err != nil || stat.Size() != offset {
+			os.Remove(temppath)
+			http.Error(w, fmt.Sprintf("Expected resume offset %d, temp file has a different size", offset), http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+	}
+
+	var reader io.Reader = part
+	if fs.MaxUploadSize > 0 {
+		reader = io.LimitReader(part, fs.MaxUploadSize-offset+1)
+	}
+
+	written, err := io.Copy(out, reader)
+	if err != nil {
+		log.Printf("ERROR:   Not able to write file to disk: %+v\n", err)
+		os.Remove(temppath)
+		fs.handle500(w, req)
+		return
+	}
+
+	if fs.MaxUploadSize > 0 && offset+written > fs.MaxUploadSize {
+		os.Remove(temppath)
+		http.Error(w, "Upload exceeds configured maximum size", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if !final {
+		w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", offset+written-1))
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	// Upload is complete: hash it and publish it atomically.
+	sum, err := sha256File(temppath)
+	if err != nil {
+		log.Printf("ERROR:   Not able to verify uploaded file: %+v\n", err)
+		os.Remove(temppath)
+		fs.handle500(w, req)
+		return
 	}
 
-	// Read file from post body
-	fileBytes, err := ioutil.ReadAll(file)
+	stat, err := os.Stat(temppath)
 	if err != nil {
-		log.Println("ERROR:   Not able to read file from request")
+		log.Printf("ERROR:   Not able to stat uploaded file: %+v\n", err)
 		fs.handle500(w, req)
+		return
 	}
 
-	// Write file to disk
-	if err := ioutil.WriteFile(savepath, fileBytes, os.ModePerm); err != nil {
-		log.Println("ERROR:   Not able to write file to disk")
+	if err := os.Rename(temppath, savepath); err != nil {
+		log.Printf("ERROR:   Not able to publish uploaded file: %+v\n", err)
+		os.Remove(temppath)
 		fs.handle500(w, req)
+		return
 	}
 
-	// Log request
-	mylog.LogRequest(req.RemoteAddr, req.Method, req.URL.Path, req.Proto, "200")
+	w.Header().Set("ETag", fmt.Sprintf(`"%s"`, sum))
+
+	if strings.Contains(req.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(uploadResult{Path: path.Join(target, filename), Size: stat.Size(), SHA256: sum})
+		return
+	}
 
 	// Redirect back from where we came from
 	http.Redirect(w, req, target, http.StatusSeeOther)
@@ -276,16 +655,14 @@ func (fs *FileServer) sendFile(w http.ResponseWriter, file *os.File) {
 }
 
 func (fs *FileServer) handle404(w http.ResponseWriter, req *http.Request) {
-	mylog.LogRequest(req.RemoteAddr, req.Method, req.URL.Path, req.Proto, "404")
-	mylog.LogMessage("404:   File not found")
+	w.WriteHeader(http.StatusNotFound)
 	t := template.New("404")
 	t.Parse(myhtml.GetTemplate("404"))
 	t.Execute(w, nil)
 }
 
 func (fs *FileServer) handle500(w http.ResponseWriter, req *http.Request) {
-	mylog.LogRequest(req.RemoteAddr, req.Method, req.URL.Path, req.Proto, "500")
-	mylog.LogMessage("500:   No permission to access the file")
+	w.WriteHeader(http.StatusInternalServerError)
 	t := template.New("500")
 	t.Parse(myhtml.GetTemplate("500"))
 	t.Execute(w, nil)
@@ -0,0 +1,139 @@
+package myhttp
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/patrickhener/goshs/internal/mylog"
+)
+
+// accessLogEntry is the shape emitted by the logging middleware when
+// --log-json is set.
+type accessLogEntry struct {
+	RequestID  string `json:"request_id"`
+	Time       string `json:"time"`
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	RemoteAddr string `json:"remote_addr"`
+	Status     int    `json:"status"`
+	Bytes      int64  `json:"bytes"`
+	DurationMS int64  `json:"duration_ms"`
+	TLSVersion string `json:"tls_version,omitempty"`
+	TLSCipher  string `json:"tls_cipher,omitempty"`
+}
+
+// responseWriter wraps http.ResponseWriter to capture the status code
+// and number of bytes written for the access log.
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (rw *responseWriter) WriteHeader(status int) {
+	rw.status = status
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	n, err := rw.ResponseWriter.Write(b)
+	rw.bytes += int64(n)
+	return n, err
+}
+
+// withLogging wraps handler with a structured access log middleware.
+// It assigns a request-id (exposed as X-Request-ID and echoed in the
+// log line), measures status code/bytes written/duration, and emits
+// either a human-readable line or a single-line JSON record depending
+// on fs.LogJSON. Logging is skipped entirely when fs.NoLogs is set.
+func (fs *FileServer) withLogging(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		requestID := uuid.NewString()
+		w.Header().Set("X-Request-ID", requestID)
+
+		if fs.NoLogs {
+			handler(w, req)
+			return
+		}
+
+		rw := &responseWriter{ResponseWriter: w}
+		start := time.Now()
+
+		handler(rw, req)
+
+		entry := accessLogEntry{
+			RequestID:  requestID,
+			Time:       start.Format(time.RFC3339),
+			Method:     req.Method,
+			Path:       req.URL.Path,
+			RemoteAddr: fs.remoteAddr(req),
+			Status:     rw.status,
+			Bytes:      rw.bytes,
+			DurationMS: time.Since(start).Milliseconds(),
+		}
+		if req.TLS != nil {
+			entry.TLSVersion = tlsVersionName(req.TLS.Version)
+			entry.TLSCipher = tls.CipherSuiteName(req.TLS.CipherSuite)
+		}
+
+		fs.emit(entry, req.Proto)
+	}
+}
+
+// emit writes entry to stdout, either as JSON or as the traditional
+// goshs access log line, depending on fs.LogJSON.
+func (fs *FileServer) emit(entry accessLogEntry, proto string) {
+	if fs.LogJSON {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			log.Printf("ERROR:   Not able to marshal access log entry: %+v\n", err)
+			return
+		}
+		fmt.Println(string(line))
+		return
+	}
+
+	mylog.LogRequest(entry.RemoteAddr, entry.Method, entry.Path, proto, fmt.Sprintf("%d (%s)", entry.Status, entry.RequestID))
+}
+
+// remoteAddr returns the client address, honoring X-Forwarded-For and
+// X-Real-IP when fs.BehindProxy is set.
+func (fs *FileServer) remoteAddr(req *http.Request) string {
+	if !fs.BehindProxy {
+		return req.RemoteAddr
+	}
+
+	if xff := req.Header.Get("X-Forwarded-For"); xff != "" {
+		return strings.TrimSpace(strings.Split(xff, ",")[0])
+	}
+	if xrip := req.Header.Get("X-Real-IP"); xrip != "" {
+		return xrip
+	}
+
+	return req.RemoteAddr
+}
+
+func tlsVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "TLS1.0"
+	case tls.VersionTLS11:
+		return "TLS1.1"
+	case tls.VersionTLS12:
+		return "TLS1.2"
+	case tls.VersionTLS13:
+		return "TLS1.3"
+	default:
+		return "unknown"
+	}
+}
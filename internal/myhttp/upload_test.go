@@ -0,0 +1,191 @@
+package myhttp
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newUploadRequest(t *testing.T, urlPath, filename string, content []byte, headers map[string]string) *http.Request {
+	t.Helper()
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	part, err := mw.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, urlPath, &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return req
+}
+
+func TestUploadStreamsAndVerifiesSHA256(t *testing.T) {
+	root := t.TempDir()
+	fs := &FileServer{Webroot: root}
+
+	content := []byte("hello, goshs")
+	req := newUploadRequest(t, "/greeting.txt", "greeting.txt", content, nil)
+	w := httptest.NewRecorder()
+
+	fs.upload(w, req)
+
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("status = %d, want %d, body = %s", w.Code, http.StatusSeeOther, w.Body.String())
+	}
+
+	got, err := os.ReadFile(filepath.Join(root, "greeting.txt"))
+	if err != nil {
+		t.Fatalf("uploaded file not found: %+v", err)
+	}
+	if string(got) != string(content) {
+		t.Fatalf("uploaded content = %q, want %q", got, content)
+	}
+
+	if w.Header().Get("ETag") == "" {
+		t.Error("ETag header not set on a completed upload")
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "greeting.txt.goshs-upload")); !os.IsNotExist(err) {
+		t.Error("temp file was not cleaned up after rename")
+	}
+}
+
+func TestUploadResumeOffsetMismatch(t *testing.T) {
+	root := t.TempDir()
+	fs := &FileServer{Webroot: root}
+
+	first := []byte("ABCDE")
+	req := newUploadRequest(t, "/big.bin", "big.bin", first, map[string]string{
+		"Content-Range": "bytes 0-4/10",
+	})
+	w := httptest.NewRecorder()
+	fs.upload(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("first chunk status = %d, want %d, body = %s", w.Code, http.StatusAccepted, w.Body.String())
+	}
+
+	// Second chunk claims a resume offset that doesn't match what was
+	// actually written so far.
+	second := []byte("XYZ")
+	req2 := newUploadRequest(t, "/big.bin", "big.bin", second, map[string]string{
+		"Content-Range": "bytes 6-8/10",
+	})
+	w2 := httptest.NewRecorder()
+	fs.upload(w2, req2)
+
+	if w2.Code != http.StatusRequestedRangeNotSatisfiable {
+		t.Fatalf("mismatched resume status = %d, want %d", w2.Code, http.StatusRequestedRangeNotSatisfiable)
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "big.bin.goshs-upload")); !os.IsNotExist(err) {
+		t.Error("temp file should be removed after a resume-offset mismatch")
+	}
+}
+
+func TestUploadAbortsOnMaxSize(t *testing.T) {
+	root := t.TempDir()
+	fs := &FileServer{Webroot: root, MaxUploadSize: 4}
+
+	req := newUploadRequest(t, "/too-big.bin", "too-big.bin", []byte("0123456789"), nil)
+	w := httptest.NewRecorder()
+	fs.upload(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want %d, body = %s", w.Code, http.StatusRequestEntityTooLarge, w.Body.String())
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "too-big.bin.goshs-upload")); !os.IsNotExist(err) {
+		t.Error("temp file should be removed after exceeding max upload size")
+	}
+	if _, err := os.Stat(filepath.Join(root, "too-big.bin")); !os.IsNotExist(err) {
+		t.Error("final file should not exist after exceeding max upload size")
+	}
+}
+
+func TestUploadIfNoneMatchRejectsExistingFile(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "exists.txt"), []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fs := &FileServer{Webroot: root}
+
+	req := newUploadRequest(t, "/exists.txt", "exists.txt", []byte("new"), map[string]string{
+		"If-None-Match": "*",
+	})
+	w := httptest.NewRecorder()
+	fs.upload(w, req)
+
+	if w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusPreconditionFailed)
+	}
+
+	got, err := os.ReadFile(filepath.Join(root, "exists.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "old" {
+		t.Fatalf("existing file was overwritten: got %q", got)
+	}
+}
+
+func TestUploadIfMatchRejectsWhenNoExistingFile(t *testing.T) {
+	root := t.TempDir()
+	fs := &FileServer{Webroot: root}
+
+	req := newUploadRequest(t, "/new.txt", "new.txt", []byte("content"), map[string]string{
+		"If-Match": `"deadbeef"`,
+	})
+	w := httptest.NewRecorder()
+	fs.upload(w, req)
+
+	if w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusPreconditionFailed)
+	}
+}
+
+func TestUploadIfMatchAllowsMatchingETag(t *testing.T) {
+	root := t.TempDir()
+	existing := []byte("same content")
+	if err := os.WriteFile(filepath.Join(root, "same.txt"), existing, 0644); err != nil {
+		t.Fatal(err)
+	}
+	sum, err := sha256File(filepath.Join(root, "same.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := &FileServer{Webroot: root}
+	req := newUploadRequest(t, "/same.txt", "same.txt", []byte("updated content"), map[string]string{
+		"If-Match": `"` + sum + `"`,
+	})
+	w := httptest.NewRecorder()
+	fs.upload(w, req)
+
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("status = %d, want %d, body = %s", w.Code, http.StatusSeeOther, w.Body.String())
+	}
+
+	got, err := os.ReadFile(filepath.Join(root, "same.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "updated content" {
+		t.Fatalf("file not updated: got %q", got)
+	}
+}
@@ -0,0 +1,74 @@
+package myhttp
+
+import (
+	"context"
+	"os"
+
+	"golang.org/x/net/webdav"
+)
+
+// safeWebdavFS wraps webdav.Dir(root) so every operation is first
+// checked by safeJoin, closing the same traversal, symlink-escape and
+// dotfile gaps safeJoin already closes for handler and upload. Without
+// this, webdav.Dir's own (much weaker) path resolution would apply
+// instead, reopening those holes whenever --webdav is enabled.
+type safeWebdavFS struct {
+	root   string
+	hidden bool
+	dir    webdav.Dir
+}
+
+// newSafeWebdavFS builds a webdav.FileSystem rooted at root whose every
+// operation is validated by safeJoin before being handed to the
+// underlying webdav.Dir.
+func newSafeWebdavFS(root string, hidden bool) *safeWebdavFS {
+	return &safeWebdavFS{root: root, hidden: hidden, dir: webdav.Dir(root)}
+}
+
+// check validates name against safeJoin, translating a rejection into
+// an error the webdav handler reports as 404, the same as handler and
+// upload do for an unsafe or hidden path.
+func (fs *safeWebdavFS) check(name string) error {
+	if _, err := safeJoin(fs.root, name, fs.hidden); err != nil {
+		return &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return nil
+}
+
+func (fs *safeWebdavFS) Mkdir(ctx context.Context, name string, perm os.FileMode) error {
+	if err := fs.check(name); err != nil {
+		return err
+	}
+	return fs.dir.Mkdir(ctx, name, perm)
+}
+
+func (fs *safeWebdavFS) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (webdav.File, error) {
+	if err := fs.check(name); err != nil {
+		return nil, err
+	}
+	return fs.dir.OpenFile(ctx, name, flag, perm)
+}
+
+func (fs *safeWebdavFS) RemoveAll(ctx context.Context, name string) error {
+	if err := fs.check(name); err != nil {
+		return err
+	}
+	return fs.dir.RemoveAll(ctx, name)
+}
+
+func (fs *safeWebdavFS) Rename(ctx context.Context, oldName, newName string) error {
+	if err := fs.check(oldName); err != nil {
+		return err
+	}
+	if err := fs.check(newName); err != nil {
+		return err
+	}
+	return fs.dir.Rename(ctx, oldName, newName)
+}
+
+func (fs *safeWebdavFS) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	if err := fs.check(name); err != nil {
+		return nil, err
+	}
+	return fs.dir.Stat(ctx, name)
+}
@@ -0,0 +1,56 @@
+package myhttp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSafeWebdavFSRejectsHiddenPath(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, ".env"), []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fs := newSafeWebdavFS(root, false)
+
+	if _, err := fs.Stat(context.Background(), "/.env"); !os.IsNotExist(err) {
+		t.Fatalf("Stat(%q) error = %v, want os.IsNotExist", "/.env", err)
+	}
+	if _, err := fs.OpenFile(context.Background(), "/.env", os.O_RDONLY, 0); !os.IsNotExist(err) {
+		t.Fatalf("OpenFile(%q) error = %v, want os.IsNotExist", "/.env", err)
+	}
+}
+
+func TestSafeWebdavFSRejectsSymlinkEscape(t *testing.T) {
+	outside := t.TempDir()
+	secret := filepath.Join(outside, "secret.txt")
+	if err := os.WriteFile(secret, []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	root := t.TempDir()
+	if err := os.Symlink(secret, filepath.Join(root, "escape.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	fs := newSafeWebdavFS(root, true)
+
+	if _, err := fs.OpenFile(context.Background(), "/escape.txt", os.O_RDONLY, 0); !os.IsNotExist(err) {
+		t.Fatalf("OpenFile(%q) error = %v, want os.IsNotExist", "/escape.txt", err)
+	}
+}
+
+func TestSafeWebdavFSAllowsOrdinaryFile(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "file.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fs := newSafeWebdavFS(root, false)
+
+	if _, err := fs.Stat(context.Background(), "/file.txt"); err != nil {
+		t.Fatalf("Stat(%q) returned error: %+v", "/file.txt", err)
+	}
+}